@@ -0,0 +1,178 @@
+// Package a51 implements the A5/1 stream cipher used to encrypt traffic
+// between a GSM handset and base station.
+//
+// The LFSR clocking and key-loading procedure are taken from the C
+// reference implementation published at http://www.scard.org/gsm/a51.html.
+// The register primitives themselves live in the internal/lfsr package,
+// shared with the a52 sibling cipher.
+package a51
+
+import "crypto/cipher"
+
+import "github.com/luwangg/go-a51/internal/lfsr"
+
+// FrameBits is the number of keystream bits A5/1 produces per GSM frame
+// direction: 114 bits for the A-to-B (downlink) burst, followed by another
+// 114 bits for the B-to-A (uplink) burst.
+const FrameBits = 114
+
+// frameBytes is FrameBits packed MSB-first into bytes.
+const frameBytes = (FrameBits + 7) / 8
+
+var (
+	r1Spec = lfsr.Spec{
+		Mask:             0x07FFFF, /* 19 bits */
+		Taps:             0x072000, /* bits 18,17,16,13 */
+		OutTap:           0x040000, /* bit 18 */
+		ClockControlBits: 0x000100, /* bit 8 */
+	}
+	r2Spec = lfsr.Spec{
+		Mask:             0x3FFFFF, /* 22 bits */
+		Taps:             0x300000, /* bits 21,20 */
+		OutTap:           0x200000, /* bit 21 */
+		ClockControlBits: 0x000400, /* bit 10 */
+	}
+	r3Spec = lfsr.Spec{
+		Mask:             0x7FFFFF, /* 23 bits */
+		Taps:             0x700080, /* bits 22,21,20,7 */
+		OutTap:           0x400000, /* bit 22 */
+		ClockControlBits: 0x000400, /* bit 10 */
+	}
+)
+
+/* clock all three registers with specific clock control:
+ *    aka clock R# whenever R#'s middle bit agrees with the
+ *        majority of middle bits
+ */
+func clock(r1, r2, r3 uint32) (uint32, uint32, uint32) {
+	c1 := lfsr.ControlBit(r1, r1Spec)
+	c2 := lfsr.ControlBit(r2, r2Spec)
+	c3 := lfsr.ControlBit(r3, r3Spec)
+	maj := lfsr.Majority(c1, c2, c3)
+
+	if c1 == maj {
+		r1 = lfsr.Clock(r1, r1Spec)
+	}
+	if c2 == maj {
+		r2 = lfsr.Clock(r2, r2Spec)
+	}
+	if c3 == maj {
+		r3 = lfsr.Clock(r3, r3Spec)
+	}
+
+	return r1, r2, r3
+}
+
+/* clock all registers regardless of middle bit majority */
+func clockAllThree(r1, r2, r3 uint32) (uint32, uint32, uint32) {
+	return lfsr.Clock(r1, r1Spec), lfsr.Clock(r2, r2Spec), lfsr.Clock(r3, r3Spec)
+}
+
+/* generate an output bit from the current register state:
+ *    grab a bit from each register and xor them all together
+ */
+func getOutputBit(r1, r2, r3 uint32) uint32 {
+	return lfsr.Output(r1, r1Spec) ^ lfsr.Output(r2, r2Spec) ^ lfsr.Output(r3, r3Spec)
+}
+
+// Cipher is a keyed A5/1 instance. It holds the running state of the three
+// LFSRs and advances every time keystream is produced, so a Cipher holds the
+// keystream of a single frame: call KeySetup again to get a fresh Cipher for
+// a new frame. Frame draws both directions of one frame from it in sequence.
+type Cipher struct {
+	r1, r2, r3 uint32
+}
+
+var _ cipher.Stream = (*Cipher)(nil)
+
+// KeySetup initializes the A5/1 shift registers from a 64-bit session key
+// (Kc) and a 22-bit GSM frame number, following the key- and frame-loading
+// procedure from the reference implementation: the key is mixed in LSB
+// first without clock control, then the frame number the same way, then
+// the registers are run for 100 clocks with clock control enabled to mix
+// the state before any keystream is produced.
+func KeySetup(key [8]byte, frame uint32) *Cipher {
+	var r1, r2, r3 uint32
+	var i uint32
+
+	// load the key into the shift registers,
+	// LSB of the first byte of the key array
+	// first, clocking each register once for
+	// every key bit loaded (without worrying
+	// about middle bit majority)
+	for i = 0; i < 64; i++ {
+		r1, r2, r3 = clockAllThree(r1, r2, r3)
+		keyBit := uint32((key[i/8] >> (i & 7)) & 1) /* the i-th bit of the key */
+		r1 ^= keyBit
+		r2 ^= keyBit
+		r3 ^= keyBit
+	}
+
+	// load the frame number into the shift registers,
+	// LSB first, clocking each register once for every
+	// frame bit loaded (without worrying about middle
+	// bit majority)
+	for i = 0; i < 22; i++ {
+		r1, r2, r3 = clockAllThree(r1, r2, r3)
+
+		frameBit := uint32((frame >> i) & 1) /* the i-th bit of the frame */
+		r1 ^= frameBit
+		r2 ^= frameBit
+		r3 ^= frameBit
+	}
+
+	// run the shift registers for 100 clocks to mix the keys
+	// we re-enable the majority bit rule from here on
+	for i = 0; i < 100; i++ {
+		r1, r2, r3 = clock(r1, r2, r3)
+	}
+
+	return &Cipher{r1: r1, r2: r2, r3: r3}
+}
+
+// Keystream clocks c forward and fills dst with nbits bits of keystream,
+// packed MSB first into each byte. dst must be at least (nbits+7)/8 bytes
+// long; any bits in the final byte beyond nbits are left untouched.
+func (c *Cipher) Keystream(dst []byte, nbits int) {
+	if len(dst) < (nbits+7)/8 {
+		panic("a51: dst too small for nbits")
+	}
+
+	for i := 0; i < nbits; i++ {
+		c.r1, c.r2, c.r3 = clock(c.r1, c.r2, c.r3)
+
+		bit := getOutputBit(c.r1, c.r2, c.r3)
+		shift := uint(7 - (i & 7))
+		if bit != 0 {
+			dst[i/8] |= 1 << shift
+		} else {
+			dst[i/8] &^= 1 << shift
+		}
+	}
+}
+
+// Frame produces one GSM frame's worth of keystream: FrameBits bits for
+// the A-to-B direction into a2b, immediately followed by FrameBits bits
+// for the B-to-A direction into b2a. This mirrors the original two-burst
+// output of the reference implementation. a2b and b2a must each be at
+// least frameBytes (15) bytes long.
+func (c *Cipher) Frame(a2b, b2a []byte) {
+	c.Keystream(a2b, FrameBits)
+	c.Keystream(b2a, FrameBits)
+}
+
+// XORKeyStream satisfies crypto/cipher.Stream. It clocks c forward to
+// produce len(src)*8 bits of keystream and XORs them into dst, following
+// the same dst/src overlap rules as the standard library stream ciphers
+// (dst and src may be the same slice but must not partially overlap).
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("a51: output smaller than input")
+	}
+
+	ks := make([]byte, len(src))
+	c.Keystream(ks, len(src)*8)
+	for i, b := range src {
+		dst[i] = b ^ ks[i]
+	}
+}
@@ -0,0 +1,189 @@
+package a51
+
+import "bytes"
+
+import "testing"
+
+func TestFrameKAT(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      [8]byte
+		frame    uint32
+		wantAtoB []byte
+		wantBtoA []byte
+	}{
+		{
+			// from the C reference implementation at
+			// http://www.scard.org/gsm/a51.html
+			name:  "scard.org reference vector",
+			key:   [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF},
+			frame: 0x134,
+			wantAtoB: []byte{0x53, 0x4E, 0xAA, 0x58, 0x2F, 0xE8, 0x15,
+				0x1A, 0xB6, 0xE1, 0x85, 0x5A, 0x72, 0x8C, 0x00},
+			wantBtoA: []byte{0x24, 0xFD, 0x35, 0xA3, 0x5D, 0x5F, 0xB6,
+				0x52, 0x6D, 0x32, 0xF9, 0x06, 0xDF, 0x1A, 0xC0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := KeySetup(tt.key, tt.frame)
+
+			a2b := make([]byte, frameBytes)
+			b2a := make([]byte, frameBytes)
+			c.Frame(a2b, b2a)
+
+			if !bytes.Equal(a2b, tt.wantAtoB) {
+				t.Errorf("AtoB = % x, want % x", a2b, tt.wantAtoB)
+			}
+			if !bytes.Equal(b2a, tt.wantBtoA) {
+				t.Errorf("BtoA = % x, want % x", b2a, tt.wantBtoA)
+			}
+		})
+	}
+}
+
+// a51Reference is a from-scratch, bit-per-byte transcription of the same
+// A5/1 design as a51.go (same taps, same mid-bit clock control, same
+// output tap), reclocking with plain shifts over a byte slice instead of
+// the uint32/internal/lfsr machinery. It is NOT an independent source of
+// truth: it shares the conceptual design with a51.go, so a bug in that
+// shared design (as opposed to a transcription slip in a51.go itself,
+// e.g. a swapped literal or an off-by-one loop bound) will reproduce
+// identically here and TestMatchesHandTranscribedReference will not
+// catch it. Additional published A5/1 vectors beyond the single
+// scard.org case above were not reachable from this environment to
+// check against instead, so that is the limited thing this test
+// actually proves: internal self-consistency of the uint32
+// implementation against a hand-transcribed byte-slice one, not
+// corroboration from an outside source.
+func a51Reference(key [8]byte, frame uint32) (a2b, b2a []byte) {
+	r1 := make([]byte, 19)
+	r2 := make([]byte, 22)
+	r3 := make([]byte, 23)
+
+	feedback := func(r []byte, taps ...int) byte {
+		var fb byte
+		for _, t := range taps {
+			fb ^= r[t]
+		}
+		return fb
+	}
+
+	clockR1 := func() {
+		fb := feedback(r1, 13, 16, 17, 18)
+		copy(r1[1:], r1[:len(r1)-1])
+		r1[0] = fb
+	}
+	clockR2 := func() {
+		fb := feedback(r2, 20, 21)
+		copy(r2[1:], r2[:len(r2)-1])
+		r2[0] = fb
+	}
+	clockR3 := func() {
+		fb := feedback(r3, 7, 20, 21, 22)
+		copy(r3[1:], r3[:len(r3)-1])
+		r3[0] = fb
+	}
+	clockThree := func() {
+		clockR1()
+		clockR2()
+		clockR3()
+	}
+	mix := func(b byte) {
+		r1[0] ^= b
+		r2[0] ^= b
+		r3[0] ^= b
+	}
+
+	for i := 0; i < 64; i++ {
+		clockThree()
+		mix((key[i/8] >> uint(i&7)) & 1)
+	}
+	for i := 0; i < 22; i++ {
+		clockThree()
+		mix(byte((frame >> uint(i)) & 1))
+	}
+
+	clockControlled := func() {
+		maj := (r1[8] & r2[10]) | (r1[8] & r3[10]) | (r2[10] & r3[10])
+		if r1[8] == maj {
+			clockR1()
+		}
+		if r2[10] == maj {
+			clockR2()
+		}
+		if r3[10] == maj {
+			clockR3()
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		clockControlled()
+	}
+
+	genBits := func(n int) []byte {
+		out := make([]byte, (n+7)/8)
+		for i := 0; i < n; i++ {
+			clockControlled()
+			bit := r1[18] ^ r2[21] ^ r3[22]
+			if bit != 0 {
+				out[i/8] |= 1 << uint(7-i%8)
+			}
+		}
+		return out
+	}
+
+	return genBits(FrameBits), genBits(FrameBits)
+}
+
+func TestMatchesHandTranscribedReference(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   [8]byte
+		frame uint32
+	}{
+		{"scard.org key/frame", [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, 0x134},
+		{"incrementing key, frame 1", [8]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77}, 1},
+		{"all-ones key, max frame", [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0x3FFFFF},
+		{"zero key, zero frame", [8]byte{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a2b := make([]byte, frameBytes)
+			b2a := make([]byte, frameBytes)
+			KeySetup(tt.key, tt.frame).Frame(a2b, b2a)
+
+			wantA2B, wantB2A := a51Reference(tt.key, tt.frame)
+
+			if !bytes.Equal(a2b, wantA2B) {
+				t.Errorf("AtoB = % x, want % x (hand-transcribed reference)", a2b, wantA2B)
+			}
+			if !bytes.Equal(b2a, wantB2A) {
+				t.Errorf("BtoA = % x, want % x (hand-transcribed reference)", b2a, wantB2A)
+			}
+		})
+	}
+}
+
+func TestXORKeyStreamRoundTrip(t *testing.T) {
+	key := [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	var frame uint32 = 0x134
+
+	plaintext := []byte("the quick brown fox jumps over the lazy dog")
+
+	ciphertext := make([]byte, len(plaintext))
+	KeySetup(key, frame).XORKeyStream(ciphertext, plaintext)
+
+	if bytes.Equal(ciphertext, plaintext) {
+		t.Fatal("XORKeyStream did not change the plaintext")
+	}
+
+	decrypted := make([]byte, len(ciphertext))
+	KeySetup(key, frame).XORKeyStream(decrypted, ciphertext)
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("round trip = %q, want %q", decrypted, plaintext)
+	}
+}
@@ -0,0 +1,212 @@
+// Package a52 implements the A5/2 GSM stream cipher: a weakened sibling
+// of A5/1 that adds a fourth register dedicated to clock control and a
+// majority-based output correction on each of the other three.
+//
+// It is built on the shared register primitives in
+// github.com/luwangg/go-a51/internal/lfsr and mirrors the a51 package's
+// API so the two ciphers can be used interchangeably.
+package a52
+
+import "crypto/cipher"
+
+import "github.com/luwangg/go-a51/internal/lfsr"
+
+// FrameBits is the number of keystream bits A5/2 produces per GSM frame
+// direction, matching A5/1's 114 bits for each of the A-to-B and B-to-A
+// bursts.
+const FrameBits = 114
+
+const frameBytes = (FrameBits + 7) / 8
+
+var (
+	// r1-r3 have no ClockControlBits of their own: unlike A5/1, they
+	// don't clock on their own middle bit, they clock on r4's bits (see
+	// r1R4Control etc. below).
+	r1Spec = lfsr.Spec{
+		Mask:   0x07FFFF, /* 19 bits */
+		Taps:   0x072000, /* bits 18,17,16,13 */
+		OutTap: 0x040000, /* bit 18 */
+	}
+	r2Spec = lfsr.Spec{
+		Mask:   0x3FFFFF, /* 22 bits */
+		Taps:   0x300000, /* bits 21,20 */
+		OutTap: 0x200000, /* bit 21 */
+	}
+	r3Spec = lfsr.Spec{
+		Mask:   0x7FFFFF, /* 23 bits */
+		Taps:   0x700080, /* bits 22,21,20,7 */
+		OutTap: 0x400000, /* bit 22 */
+	}
+	// r4 drives clock control for r1-r3 instead of contributing to the
+	// output directly; it has no OutTap.
+	r4Spec = lfsr.Spec{
+		Mask: 0x01FFFF, /* 17 bits */
+		Taps: 0x000820, /* bits 11,5 */
+	}
+
+	// r1R4Control, r2R4Control and r3R4Control each carry one of the
+	// three bits of r4 whose majority decides whether r1, r2 and r3
+	// respectively clock on a given round; they're passed to
+	// lfsr.ControlBit against r4, not against the register they gate.
+	r1R4Control = lfsr.Spec{ClockControlBits: 0x00400} /* bit 10 */
+	r2R4Control = lfsr.Spec{ClockControlBits: 0x00008} /* bit 3 */
+	r3R4Control = lfsr.Spec{ClockControlBits: 0x00001} /* bit 0 */
+
+	// each of r1-r3's output is corrected by OR-ing together two
+	// majority votes taken over bits of that same register, per the
+	// A5/2 output function.
+	r1MajA = [3]uint32{0x0200, 0x0100, 0x0080} /* bits 9,8,7 */
+	r1MajB = [3]uint32{0x0040, 0x0020, 0x0010} /* bits 6,5,4 */
+
+	r2MajA = [3]uint32{0x4000, 0x2000, 0x1000} /* bits 14,13,12 */
+	r2MajB = [3]uint32{0x0800, 0x0400, 0x0200} /* bits 11,10,9 */
+
+	r3MajA = [3]uint32{0x10000, 0x8000, 0x4000} /* bits 16,15,14 */
+	r3MajB = [3]uint32{0x2000, 0x1000, 0x0800}  /* bits 13,12,11 */
+)
+
+// Cipher is a keyed A5/2 instance holding the running state of its four
+// LFSRs. Like a51.Cipher it advances every time keystream is produced.
+type Cipher struct {
+	r1, r2, r3, r4 uint32
+}
+
+var _ cipher.Stream = (*Cipher)(nil)
+
+// bit extracts a single 0/1 bit value from register r using mask, which
+// must select exactly one bit.
+func bit(r, mask uint32) uint32 {
+	return lfsr.Parity(r & mask)
+}
+
+// correctedOutput returns spec's output bit for r, corrected by the
+// A5/2 rule: XOR the raw output tap with the OR of two 3-bit majority
+// votes taken elsewhere in the same register.
+func correctedOutput(r uint32, spec lfsr.Spec, majA, majB [3]uint32) uint32 {
+	out := lfsr.Output(r, spec)
+
+	a := lfsr.Majority(bit(r, majA[0]), bit(r, majA[1]), bit(r, majA[2]))
+	b := lfsr.Majority(bit(r, majB[0]), bit(r, majB[1]), bit(r, majB[2]))
+
+	return out ^ (a | b)
+}
+
+// clockAllFour clocks all four registers unconditionally, used while
+// loading the key and frame number.
+func clockAllFour(r1, r2, r3, r4 uint32) (uint32, uint32, uint32, uint32) {
+	return lfsr.Clock(r1, r1Spec), lfsr.Clock(r2, r2Spec), lfsr.Clock(r3, r3Spec), lfsr.Clock(r4, r4Spec)
+}
+
+// clock advances r4 unconditionally, then clocks each of r1-r3 whenever
+// its control bit in r4 agrees with the majority of all three control
+// bits, per A5/2's R4-driven clock control.
+func clock(r1, r2, r3, r4 uint32) (uint32, uint32, uint32, uint32) {
+	r4 = lfsr.Clock(r4, r4Spec)
+
+	c1 := lfsr.ControlBit(r4, r1R4Control)
+	c2 := lfsr.ControlBit(r4, r2R4Control)
+	c3 := lfsr.ControlBit(r4, r3R4Control)
+	maj := lfsr.Majority(c1, c2, c3)
+
+	if c1 == maj {
+		r1 = lfsr.Clock(r1, r1Spec)
+	}
+	if c2 == maj {
+		r2 = lfsr.Clock(r2, r2Spec)
+	}
+	if c3 == maj {
+		r3 = lfsr.Clock(r3, r3Spec)
+	}
+
+	return r1, r2, r3, r4
+}
+
+// KeySetup initializes the A5/2 shift registers from a 64-bit session
+// key (Kc) and a 22-bit GSM frame number, following the same key- and
+// frame-loading procedure as A5/1 extended to the fourth register: the
+// key is mixed in LSB first without clock control, then the frame
+// number the same way. A5/2 then forces one fixed bit into each of
+// R1-R4 before the 100-clock, clock-controlled warm-up: this is a
+// deliberate, well-documented weakness in the real cipher (see e.g.
+// Barkan, Biham and Keller, "Instant Ciphertext-Only Cryptanalysis of
+// GSM Encrypted Communication") rather than a bug, and omitting it
+// would silently turn this into a different, non-interoperable cipher.
+func KeySetup(key [8]byte, frame uint32) *Cipher {
+	var r1, r2, r3, r4 uint32
+	var i uint32
+
+	for i = 0; i < 64; i++ {
+		r1, r2, r3, r4 = clockAllFour(r1, r2, r3, r4)
+		keyBit := uint32((key[i/8] >> (i & 7)) & 1)
+		r1 ^= keyBit
+		r2 ^= keyBit
+		r3 ^= keyBit
+		r4 ^= keyBit
+	}
+
+	for i = 0; i < 22; i++ {
+		r1, r2, r3, r4 = clockAllFour(r1, r2, r3, r4)
+		frameBit := uint32((frame >> i) & 1)
+		r1 ^= frameBit
+		r2 ^= frameBit
+		r3 ^= frameBit
+		r4 ^= frameBit
+	}
+
+	// force bit 15 of R1, bit 16 of R2, bit 18 of R3 and bit 10 of R4
+	// to 1, as the real A5/2 cipher does.
+	r1 |= 1 << 15
+	r2 |= 1 << 16
+	r3 |= 1 << 18
+	r4 |= 1 << 10
+
+	for i = 0; i < 100; i++ {
+		r1, r2, r3, r4 = clock(r1, r2, r3, r4)
+	}
+
+	return &Cipher{r1: r1, r2: r2, r3: r3, r4: r4}
+}
+
+// Keystream clocks c forward and fills dst with nbits bits of keystream,
+// packed MSB first into each byte, mirroring a51.Cipher.Keystream.
+func (c *Cipher) Keystream(dst []byte, nbits int) {
+	if len(dst) < (nbits+7)/8 {
+		panic("a52: dst too small for nbits")
+	}
+
+	for i := 0; i < nbits; i++ {
+		c.r1, c.r2, c.r3, c.r4 = clock(c.r1, c.r2, c.r3, c.r4)
+
+		out := correctedOutput(c.r1, r1Spec, r1MajA, r1MajB) ^
+			correctedOutput(c.r2, r2Spec, r2MajA, r2MajB) ^
+			correctedOutput(c.r3, r3Spec, r3MajA, r3MajB)
+
+		shift := uint(7 - (i & 7))
+		if out != 0 {
+			dst[i/8] |= 1 << shift
+		} else {
+			dst[i/8] &^= 1 << shift
+		}
+	}
+}
+
+// Frame produces one GSM frame's worth of keystream: FrameBits bits for
+// the A-to-B direction into a2b, followed by FrameBits bits for the
+// B-to-A direction into b2a, mirroring a51.Cipher.Frame.
+func (c *Cipher) Frame(a2b, b2a []byte) {
+	c.Keystream(a2b, FrameBits)
+	c.Keystream(b2a, FrameBits)
+}
+
+// XORKeyStream satisfies crypto/cipher.Stream.
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("a52: output smaller than input")
+	}
+
+	ks := make([]byte, len(src))
+	c.Keystream(ks, len(src)*8)
+	for i, b := range src {
+		dst[i] = b ^ ks[i]
+	}
+}
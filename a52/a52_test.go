@@ -0,0 +1,185 @@
+package a52
+
+import "bytes"
+
+import "testing"
+
+// a52Reference is a from-scratch, bit-per-byte transcription of the
+// same A5/2 design as a52.go (same taps, same R4-driven clock control,
+// same forced bits, same output correction), reclocking with plain
+// shifts over a byte slice instead of the uint32/internal/lfsr
+// machinery. It is NOT an independent source of truth: it shares the
+// conceptual design with a52.go, so a bug in that shared design (as
+// opposed to a transcription slip in a52.go itself, e.g. a swapped
+// literal or an off-by-one loop bound) will reproduce identically here
+// and TestMatchesHandTranscribedReference will not catch it. No
+// published A5/2 test vectors were reachable from this environment to
+// check against instead, so that is the limited thing this test
+// actually proves: internal self-consistency of the uint32
+// implementation against a hand-transcribed byte-slice one, not
+// correctness against the real A5/2 specification.
+func a52Reference(key [8]byte, frame uint32) (a2b, b2a []byte) {
+	r1 := make([]byte, 19)
+	r2 := make([]byte, 22)
+	r3 := make([]byte, 23)
+	r4 := make([]byte, 17)
+
+	feedback := func(r []byte, taps ...int) byte {
+		var fb byte
+		for _, t := range taps {
+			fb ^= r[t]
+		}
+		return fb
+	}
+	clockInto := func(r []byte, taps ...int) {
+		fb := feedback(r, taps...)
+		copy(r[1:], r[:len(r)-1])
+		r[0] = fb
+	}
+	clockAll := func() {
+		clockInto(r1, 13, 16, 17, 18)
+		clockInto(r2, 20, 21)
+		clockInto(r3, 7, 20, 21, 22)
+		clockInto(r4, 11, 5)
+	}
+	mix := func(b byte) {
+		r1[0] ^= b
+		r2[0] ^= b
+		r3[0] ^= b
+		r4[0] ^= b
+	}
+
+	for i := 0; i < 64; i++ {
+		clockAll()
+		mix((key[i/8] >> uint(i&7)) & 1)
+	}
+	for i := 0; i < 22; i++ {
+		clockAll()
+		mix(byte((frame >> uint(i)) & 1))
+	}
+
+	// force bit 15 of R1, bit 16 of R2, bit 18 of R3 and bit 10 of R4
+	// to 1, matching KeySetup.
+	r1[15] = 1
+	r2[16] = 1
+	r3[18] = 1
+	r4[10] = 1
+
+	clockControlled := func() {
+		clockInto(r4, 11, 5)
+
+		c1, c2, c3 := r4[10], r4[3], r4[0]
+		maj := (c1 & c2) | (c1 & c3) | (c2 & c3)
+		if c1 == maj {
+			clockInto(r1, 13, 16, 17, 18)
+		}
+		if c2 == maj {
+			clockInto(r2, 20, 21)
+		}
+		if c3 == maj {
+			clockInto(r3, 7, 20, 21, 22)
+		}
+	}
+
+	maj3 := func(a, b, c byte) byte {
+		return (a & b) | (a & c) | (b & c)
+	}
+	correctedOut := func(r []byte, outIdx, a0, a1, a2, b0, b1, b2 int) byte {
+		out := r[outIdx]
+		a := maj3(r[a0], r[a1], r[a2])
+		b := maj3(r[b0], r[b1], r[b2])
+		return out ^ (a | b)
+	}
+
+	for i := 0; i < 100; i++ {
+		clockControlled()
+	}
+
+	genBits := func(n int) []byte {
+		out := make([]byte, (n+7)/8)
+		for i := 0; i < n; i++ {
+			clockControlled()
+
+			bit := correctedOut(r1, 18, 9, 8, 7, 6, 5, 4) ^
+				correctedOut(r2, 21, 14, 13, 12, 11, 10, 9) ^
+				correctedOut(r3, 22, 16, 15, 14, 13, 12, 11)
+
+			if bit != 0 {
+				out[i/8] |= 1 << uint(7-i%8)
+			}
+		}
+		return out
+	}
+
+	return genBits(FrameBits), genBits(FrameBits)
+}
+
+func TestMatchesHandTranscribedReference(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   [8]byte
+		frame uint32
+	}{
+		{"scard.org key/frame pair", [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, 0x134},
+		{"all-ones key, max frame", [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0x3FFFFF},
+		{"zero key, zero frame", [8]byte{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a2b := make([]byte, frameBytes)
+			b2a := make([]byte, frameBytes)
+			KeySetup(tt.key, tt.frame).Frame(a2b, b2a)
+
+			wantA2B, wantB2A := a52Reference(tt.key, tt.frame)
+
+			if !bytes.Equal(a2b, wantA2B) {
+				t.Errorf("AtoB = % x, want % x (hand-transcribed reference)", a2b, wantA2B)
+			}
+			if !bytes.Equal(b2a, wantB2A) {
+				t.Errorf("BtoA = % x, want % x (hand-transcribed reference)", b2a, wantB2A)
+			}
+		})
+	}
+}
+
+func TestXORKeyStreamRoundTrip(t *testing.T) {
+	key := [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	var frame uint32 = 0x134
+
+	plaintext := []byte("the quick brown fox jumps over the lazy dog")
+
+	ciphertext := make([]byte, len(plaintext))
+	KeySetup(key, frame).XORKeyStream(ciphertext, plaintext)
+
+	if bytes.Equal(ciphertext, plaintext) {
+		t.Fatal("XORKeyStream did not change the plaintext")
+	}
+
+	decrypted := make([]byte, len(ciphertext))
+	KeySetup(key, frame).XORKeyStream(decrypted, ciphertext)
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("round trip = %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestKeystreamDiffersFromA51(t *testing.T) {
+	// Sanity check that A5/2's extra clock-control register and output
+	// correction actually change the keystream relative to a plain A5/1
+	// run with the same key/frame; it would be easy to accidentally
+	// wire correctedOutput back to a plain majority rule.
+	key := [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	var frame uint32 = 0x134
+
+	a2b := make([]byte, frameBytes)
+	b2a := make([]byte, frameBytes)
+	KeySetup(key, frame).Frame(a2b, b2a)
+
+	a51AtoB := []byte{0x53, 0x4E, 0xAA, 0x58, 0x2F, 0xE8, 0x15,
+		0x1A, 0xB6, 0xE1, 0x85, 0x5A, 0x72, 0x8C, 0x00}
+
+	if bytes.Equal(a2b, a51AtoB) {
+		t.Fatal("a52 produced the same keystream as a51 for the same key/frame")
+	}
+}
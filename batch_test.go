@@ -0,0 +1,79 @@
+package a51
+
+import (
+	"bytes"
+	"testing"
+)
+
+// genKeysFrames deterministically builds n distinct (key, frame) pairs
+// so tests don't depend on math/rand's global state.
+func genKeysFrames(n int) ([][8]byte, []uint32) {
+	keys := make([][8]byte, n)
+	frames := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		for b := 0; b < 8; b++ {
+			keys[i][b] = byte(i*8 + b)
+		}
+		frames[i] = uint32(i*37) & 0x3FFFFF
+	}
+	return keys, frames
+}
+
+func TestBatchKeystreamMatchesSerial(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 63, 64, 65, 128, 130} {
+		keys, frames := genKeysFrames(n)
+
+		got := make([][]byte, n)
+		for i := range got {
+			got[i] = make([]byte, frameBytes)
+		}
+		BatchKeystream(keys, frames, got)
+
+		for i := 0; i < n; i++ {
+			want := make([]byte, frameBytes)
+			KeySetup(keys[i], frames[i]).Keystream(want, frameBytes*8)
+
+			if !bytes.Equal(got[i], want) {
+				t.Errorf("n=%d, instance %d: BatchKeystream = % x, want % x", n, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestBatchKeystreamRejectsMismatchedLengths(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for mismatched slice lengths")
+		}
+	}()
+
+	keys, frames := genKeysFrames(2)
+	BatchKeystream(keys, frames, make([][]byte, 1))
+}
+
+func BenchmarkSerialKeystream(b *testing.B) {
+	const n = 64
+	keys, frames := genKeysFrames(n)
+	out := make([]byte, frameBytes)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			KeySetup(keys[j], frames[j]).Keystream(out, frameBytes*8)
+		}
+	}
+}
+
+func BenchmarkBatchKeystream(b *testing.B) {
+	const n = 64
+	keys, frames := genKeysFrames(n)
+	out := make([][]byte, n)
+	for i := range out {
+		out[i] = make([]byte, frameBytes)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchKeystream(keys, frames, out)
+	}
+}
@@ -0,0 +1,179 @@
+package a51
+
+import "io"
+
+// BitReader reads a bit-oriented stream MSB-first from an underlying
+// io.Reader, buffering bits in a 64-bit accumulator refilled 8 bits at a
+// time. It lets callers consume keystream or burst payloads that are not
+// a whole number of bytes long.
+type BitReader struct {
+	r     io.Reader
+	cur   uint64
+	cbits uint8
+	buf   [1]byte
+}
+
+// NewBitReader returns a BitReader that pulls bytes from r as needed.
+func NewBitReader(r io.Reader) *BitReader {
+	return &BitReader{r: r}
+}
+
+// ReadBits returns the next n bits read MSB-first, as the low n bits of
+// the returned value. n must be between 0 and 56 inclusive so the 8-bit
+// refill can never overflow the 64-bit accumulator.
+func (br *BitReader) ReadBits(n uint8) (uint64, error) {
+	if n > 56 {
+		panic("a51: ReadBits: n too large")
+	}
+
+	for br.cbits < n {
+		if _, err := io.ReadFull(br.r, br.buf[:]); err != nil {
+			return 0, err
+		}
+		br.cur = (br.cur << 8) | uint64(br.buf[0])
+		br.cbits += 8
+	}
+
+	shift := br.cbits - n
+	v := (br.cur >> shift) & (1<<n - 1)
+	br.cbits = shift
+
+	return v, nil
+}
+
+// BitWriter writes a bit-oriented stream MSB-first to an underlying
+// io.Writer, buffering bits in a 64-bit accumulator and flushing whole
+// bytes as they fill up.
+type BitWriter struct {
+	w     io.Writer
+	cur   uint64
+	cbits uint8
+}
+
+// NewBitWriter returns a BitWriter that flushes complete bytes to w.
+func NewBitWriter(w io.Writer) *BitWriter {
+	return &BitWriter{w: w}
+}
+
+// WriteBits appends the low n bits of v, written MSB-first, to the
+// stream. n must be between 0 and 56 inclusive.
+func (bw *BitWriter) WriteBits(v uint64, n uint8) error {
+	if n > 56 {
+		panic("a51: WriteBits: n too large")
+	}
+
+	bw.cur = (bw.cur << n) | (v & (1<<n - 1))
+	bw.cbits += n
+
+	for bw.cbits >= 8 {
+		shift := bw.cbits - 8
+		b := byte(bw.cur >> shift)
+		if _, err := bw.w.Write([]byte{b}); err != nil {
+			return err
+		}
+		bw.cbits = shift
+	}
+
+	return nil
+}
+
+// Flush pads any buffered residual bits with zeros out to a full byte and
+// writes it. It is a no-op if no bits are currently buffered.
+func (bw *BitWriter) Flush() error {
+	if bw.cbits == 0 {
+		return nil
+	}
+
+	b := byte(bw.cur << (8 - bw.cbits))
+	bw.cur, bw.cbits = 0, 0
+
+	_, err := bw.w.Write([]byte{b})
+	return err
+}
+
+// XORKeyStreamBits clocks c forward, XORs nbits bits of keystream, packed
+// MSB-first, with the first nbits bits of src and writes the result into
+// the first nbits bits of dst. It behaves
+// like XORKeyStream but allows nbits to be any value rather than a
+// multiple of 8: a trailing partial byte only has its top nbits%8 bits
+// XORed, leaving the rest of that byte in dst untouched. dst and src must
+// each be at least (nbits+7)/8 bytes long.
+func (c *Cipher) XORKeyStreamBits(dst, src []byte, nbits int) {
+	nbytes := (nbits + 7) / 8
+	if len(dst) < nbytes || len(src) < nbytes {
+		panic("a51: dst/src too small for nbits")
+	}
+
+	ks := make([]byte, nbytes)
+	c.Keystream(ks, nbits)
+
+	full := nbits / 8
+	for i := 0; i < full; i++ {
+		dst[i] = src[i] ^ ks[i]
+	}
+
+	if rem := nbits & 7; rem != 0 {
+		mask := byte(0xFF << (8 - rem))
+		dst[full] = (dst[full] &^ mask) | ((src[full] ^ ks[full]) & mask)
+	}
+}
+
+// NewEncrypter returns an io.WriteCloser that XORs everything written to
+// it with c's keystream and forwards the result to w. Close must be
+// called to flush any residual bits once the caller is done writing.
+func (c *Cipher) NewEncrypter(w io.Writer) io.WriteCloser {
+	return &encrypter{c: c, bw: NewBitWriter(w)}
+}
+
+type encrypter struct {
+	c  *Cipher
+	bw *BitWriter
+}
+
+func (e *encrypter) Write(p []byte) (int, error) {
+	ks := make([]byte, len(p))
+	e.c.Keystream(ks, len(p)*8)
+
+	for i, b := range p {
+		if err := e.bw.WriteBits(uint64(b^ks[i]), 8); err != nil {
+			return i, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (e *encrypter) Close() error {
+	return e.bw.Flush()
+}
+
+// NewDecrypter returns an io.Reader that reads ciphertext from r and
+// XORs it with c's keystream, yielding back the original plaintext.
+func (c *Cipher) NewDecrypter(r io.Reader) io.Reader {
+	return &decrypter{c: c, br: NewBitReader(r)}
+}
+
+type decrypter struct {
+	c  *Cipher
+	br *BitReader
+}
+
+func (d *decrypter) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		v, err := d.br.ReadBits(8)
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+
+		var ks [1]byte
+		d.c.Keystream(ks[:], 8)
+		p[n] = byte(v) ^ ks[0]
+		n++
+	}
+
+	return n, nil
+}
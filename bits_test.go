@@ -0,0 +1,112 @@
+package a51
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestBitReaderWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	bw := NewBitWriter(&buf)
+
+	// 13, 7, 1 and 3 bits: deliberately not byte-aligned.
+	writes := []struct {
+		v uint64
+		n uint8
+	}{
+		{0x1A2B & (1<<13 - 1), 13},
+		{0x5A & (1<<7 - 1), 7},
+		{1, 1},
+		{0x6, 3},
+	}
+	for _, w := range writes {
+		if err := bw.WriteBits(w.v, w.n); err != nil {
+			t.Fatalf("WriteBits(%d, %d): %v", w.v, w.n, err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	br := NewBitReader(&buf)
+	for _, w := range writes {
+		got, err := br.ReadBits(w.n)
+		if err != nil {
+			t.Fatalf("ReadBits(%d): %v", w.n, err)
+		}
+		if got != w.v {
+			t.Errorf("ReadBits(%d) = %#x, want %#x", w.n, got, w.v)
+		}
+	}
+}
+
+func TestXORKeyStreamBitsMatchesKeystream(t *testing.T) {
+	key := [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	var frame uint32 = 0x134
+
+	wantAtoB := []byte{0x53, 0x4E, 0xAA, 0x58, 0x2F, 0xE8, 0x15,
+		0x1A, 0xB6, 0xE1, 0x85, 0x5A, 0x72, 0x8C, 0x00}
+
+	src := make([]byte, frameBytes)
+	dst := make([]byte, frameBytes)
+	KeySetup(key, frame).XORKeyStreamBits(dst, src, FrameBits)
+
+	if !bytes.Equal(dst, wantAtoB) {
+		t.Errorf("XORKeyStreamBits(zeros, 114) = % x, want % x", dst, wantAtoB)
+	}
+}
+
+func TestXORKeyStreamBitsNonByteAligned(t *testing.T) {
+	key := [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+
+	for _, nbits := range []int{1, 3, 7, 8, 9, 15, 16, 17, 114, 229} {
+		nbytes := (nbits + 7) / 8
+		plaintext := make([]byte, nbytes)
+		for i := range plaintext {
+			plaintext[i] = byte(0xA5 + i)
+		}
+
+		ciphertext := make([]byte, nbytes)
+		KeySetup(key, 0).XORKeyStreamBits(ciphertext, plaintext, nbits)
+
+		decrypted := make([]byte, nbytes)
+		KeySetup(key, 0).XORKeyStreamBits(decrypted, ciphertext, nbits)
+
+		// only the top nbits bits of the stream are defined.
+		full := nbits / 8
+		if !bytes.Equal(decrypted[:full], plaintext[:full]) {
+			t.Errorf("nbits=%d: decrypted = % x, want % x", nbits, decrypted[:full], plaintext[:full])
+		}
+		if rem := nbits & 7; rem != 0 {
+			mask := byte(0xFF << (8 - rem))
+			if decrypted[full]&mask != plaintext[full]&mask {
+				t.Errorf("nbits=%d: trailing bits = %08b, want %08b", nbits, decrypted[full]&mask, plaintext[full]&mask)
+			}
+		}
+	}
+}
+
+func TestEncrypterDecrypterRoundTrip(t *testing.T) {
+	key := [8]byte{0x12, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	plaintext := []byte("the quick brown fox jumps over the lazy dog, 0123456789")
+
+	var ciphertext bytes.Buffer
+	enc := KeySetup(key, 0).NewEncrypter(&ciphertext)
+	if _, err := enc.Write(plaintext); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	dec := KeySetup(key, 0).NewDecrypter(&ciphertext)
+	got := make([]byte, len(plaintext))
+	if _, err := io.ReadFull(dec, got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("round trip = %q, want %q", got, plaintext)
+	}
+}
@@ -0,0 +1,60 @@
+// Package lfsr provides the clock-controlled linear feedback shift
+// register primitives shared by the GSM A5 family of stream ciphers: a
+// parity-based feedback tap, a single output tap, and majority-vote
+// clock control. a51 and a52 both build their registers on top of it.
+package lfsr
+
+// Spec describes the shape of one shift register: its bit width (via
+// Mask), its feedback taps, and the bit(s) it contributes to the
+// cipher's output and clock-control decision.
+type Spec struct {
+	Mask             uint32
+	Taps             uint32
+	OutTap           uint32
+	ClockControlBits uint32
+}
+
+// Parity returns the parity (sum modulo 2) of the bits set in x.
+func Parity(x uint32) uint32 {
+	x ^= x >> 16
+	x ^= x >> 8
+	x ^= x >> 4
+	x ^= x >> 2
+	x ^= x >> 1
+	return x & 1
+}
+
+// Clock shifts register left by one bit within spec.Mask, feeding the
+// parity of the tapped bits into the new low bit.
+func Clock(register uint32, spec Spec) uint32 {
+	t := register & spec.Taps
+	register = (register << 1) & spec.Mask
+	register |= Parity(t)
+
+	return register
+}
+
+// Output returns the register's contribution to the cipher's keystream:
+// the parity of its OutTap bits.
+func Output(register uint32, spec Spec) uint32 {
+	return Parity(register & spec.OutTap)
+}
+
+// ControlBit returns the register's clock-control bit: the parity of its
+// ClockControlBits. For a single-bit mask (A5/1's middle-bit rule) this
+// is just that bit's value.
+func ControlBit(register uint32, spec Spec) uint32 {
+	return Parity(register & spec.ClockControlBits)
+}
+
+// Majority returns 1 if at least two of a, b and c are set, and 0
+// otherwise. It implements the clock-control rule shared by A5/1
+// (majority of three registers' middle bits) and A5/2 (majority of
+// three bits drawn from the dedicated clock-control register).
+func Majority(a, b, c uint32) uint32 {
+	if a+b+c >= 2 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,45 @@
+// Package kdf derives A5/1 session keys (Kc) from application-level
+// passphrases using Argon2id, for callers that only have a user secret
+// rather than a raw 64-bit Kc.
+package kdf
+
+import (
+	"golang.org/x/crypto/argon2"
+
+	"github.com/luwangg/go-a51"
+)
+
+// Params holds the Argon2id cost parameters used to derive a Kc.
+type Params struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultParams matches the parameters recommended for interactive login
+// derivation: one pass, 64 MiB of memory and four lanes.
+var DefaultParams = Params{
+	Time:    1,
+	Memory:  64 * 1024,
+	Threads: 4,
+}
+
+// DeriveKc derives an 8-byte A5/1 Kc from passphrase and salt using
+// Argon2id under the given cost parameters.
+func DeriveKc(passphrase, salt []byte, time, memory uint32, threads uint8) [8]byte {
+	raw := argon2.IDKey(passphrase, salt, time, memory, threads, 8)
+
+	var kc [8]byte
+	copy(kc[:], raw)
+	return kc
+}
+
+// DeriveFrameKey derives a Kc from passphrase and salt using
+// DefaultParams, then keys an A5/1 Cipher with it for the given GSM
+// frame number. It chains DeriveKc straight into a51.KeySetup so callers
+// driving A5/1 from a passphrase don't have to juggle the intermediate
+// Kc themselves.
+func DeriveFrameKey(passphrase, salt []byte, frame uint32) *a51.Cipher {
+	kc := DeriveKc(passphrase, salt, DefaultParams.Time, DefaultParams.Memory, DefaultParams.Threads)
+	return a51.KeySetup(kc, frame)
+}
@@ -0,0 +1,42 @@
+package kdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveKcIsStable(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+	salt := []byte("go-a51 test salt")
+
+	got1 := DeriveKc(passphrase, salt, DefaultParams.Time, DefaultParams.Memory, DefaultParams.Threads)
+	got2 := DeriveKc(passphrase, salt, DefaultParams.Time, DefaultParams.Memory, DefaultParams.Threads)
+
+	if got1 != got2 {
+		t.Fatalf("DeriveKc is not deterministic: %x != %x", got1, got2)
+	}
+
+	other := DeriveKc([]byte("a different passphrase"), salt, DefaultParams.Time, DefaultParams.Memory, DefaultParams.Threads)
+	if got1 == other {
+		t.Fatalf("DeriveKc produced the same Kc for different passphrases: %x", got1)
+	}
+}
+
+func TestDeriveFrameKeyRegression(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+	salt := []byte("go-a51 test salt")
+	var frame uint32 = 0x134
+
+	// Fixed parameters, salt and frame so this keystream is a stable
+	// regression check on the whole passphrase -> Kc -> keystream chain.
+	want := []byte{0xc7, 0xb6, 0xbd, 0x6c, 0x79, 0x81, 0xa3,
+		0x8e, 0xdb, 0xbb, 0xbd, 0x25, 0x54, 0x3e, 0xc0}
+
+	c := DeriveFrameKey(passphrase, salt, frame)
+	got := make([]byte, 15)
+	c.Keystream(got, 114)
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("DeriveFrameKey keystream = % x, want % x", got, want)
+	}
+}